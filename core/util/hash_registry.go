@@ -0,0 +1,146 @@
+/*
+Copyright IBM Corp. 2016 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"sort"
+	"sync"
+
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/sha3"
+)
+
+// hashRegistry holds the hash algorithm factories a peer knows about. It is
+// seeded with the built-ins below and may be extended by BCCSP providers or
+// other plugins via RegisterHashAlgorithm.
+var hashRegistry = struct {
+	sync.RWMutex
+	factories map[string]func() hash.Hash
+	defAlg    string
+}{
+	factories: make(map[string]func() hash.Hash),
+	defAlg:    "shake256",
+}
+
+// shakeSum adapts a variable-length sha3.ShakeHash to the fixed-output
+// hash.Hash interface expected by the registry. size is the fixed digest
+// length; SHAKE256 uses the 64 bytes ComputeCryptoHash has always produced.
+type shakeSum struct {
+	sha3.ShakeHash
+	size int
+}
+
+func (s *shakeSum) Sum(b []byte) []byte {
+	out := make([]byte, s.size)
+	s.ShakeHash.Clone().Read(out)
+	return append(b, out...)
+}
+
+func (s *shakeSum) Size() int { return s.size }
+
+func mustBlake2b256() hash.Hash {
+	h, err := blake2b.New256(nil)
+	if err != nil {
+		panic(fmt.Sprintf("util: unexpected error creating blake2b-256: %s", err))
+	}
+	return h
+}
+
+func mustBlake2b512() hash.Hash {
+	h, err := blake2b.New512(nil)
+	if err != nil {
+		panic(fmt.Sprintf("util: unexpected error creating blake2b-512: %s", err))
+	}
+	return h
+}
+
+func init() {
+	RegisterHashAlgorithm("sha256", sha256.New)
+	RegisterHashAlgorithm("sha384", sha512.New384)
+	RegisterHashAlgorithm("sha512", sha512.New)
+	RegisterHashAlgorithm("sha3-256", sha3.New256)
+	RegisterHashAlgorithm("sha3-384", sha3.New384)
+	RegisterHashAlgorithm("sha3-512", sha3.New512)
+	RegisterHashAlgorithm("shake128", func() hash.Hash { return &shakeSum{sha3.NewShake128(), 32} })
+	RegisterHashAlgorithm("shake256", func() hash.Hash { return &shakeSum{sha3.NewShake256(), 64} })
+	RegisterHashAlgorithm("blake2b-256", mustBlake2b256)
+	RegisterHashAlgorithm("blake2b-512", mustBlake2b512)
+}
+
+// RegisterHashAlgorithm makes a hash.Hash factory available under name to
+// ComputeCryptoHashWith and, once selected via SetDefaultHashAlgorithm, to
+// ComputeCryptoHash. It is safe to call concurrently and may be used to
+// override a built-in registration.
+func RegisterHashAlgorithm(name string, factory func() hash.Hash) {
+	hashRegistry.Lock()
+	defer hashRegistry.Unlock()
+	hashRegistry.factories[name] = factory
+}
+
+// ListHashAlgorithms returns the names of every hash algorithm currently
+// registered, sorted for stable output in chaincode lifecycle logs and
+// admin CLI listings.
+func ListHashAlgorithms() []string {
+	hashRegistry.RLock()
+	defer hashRegistry.RUnlock()
+	names := make([]string, 0, len(hashRegistry.factories))
+	for name := range hashRegistry.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// currentHashAlg returns the name most recently selected with
+// SetDefaultHashAlgorithm, taking hashRegistry.RLock() so callers never
+// race with a concurrent SetDefaultHashAlgorithm.
+func currentHashAlg() string {
+	hashRegistry.RLock()
+	defer hashRegistry.RUnlock()
+	return hashRegistry.defAlg
+}
+
+// SetDefaultHashAlgorithm selects the algorithm ComputeCryptoHash uses, e.g.
+// from a BCCSP.Hash configuration value read at process start. It returns
+// an error if name has not been registered.
+func SetDefaultHashAlgorithm(name string) error {
+	hashRegistry.Lock()
+	defer hashRegistry.Unlock()
+	if _, ok := hashRegistry.factories[name]; !ok {
+		return fmt.Errorf("util: unknown hash algorithm %q", name)
+	}
+	hashRegistry.defAlg = name
+	return nil
+}
+
+// ComputeCryptoHashWith computes the digest of data using the named
+// algorithm, returning an error if name is not registered.
+func ComputeCryptoHashWith(name string, data []byte) ([]byte, error) {
+	hashRegistry.RLock()
+	factory, ok := hashRegistry.factories[name]
+	hashRegistry.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("util: unknown hash algorithm %q", name)
+	}
+	h := factory()
+	h.Write(data)
+	return h.Sum(nil), nil
+}
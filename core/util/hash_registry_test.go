@@ -0,0 +1,123 @@
+/*
+Copyright IBM Corp. 2016 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"crypto/sha256"
+	"hash"
+	"testing"
+)
+
+// constHash is a minimal hash.Hash whose Sum is fixed, used to verify that
+// RegisterHashAlgorithm can override an existing registration.
+type constHash struct{}
+
+func (constHash) Write(p []byte) (int, error) { return len(p), nil }
+func (constHash) Sum(b []byte) []byte         { return append(b, 'x') }
+func (constHash) Reset()                      {}
+func (constHash) Size() int                   { return 1 }
+func (constHash) BlockSize() int              { return 1 }
+
+func TestComputeCryptoHashWithLengths(t *testing.T) {
+	data := []byte("fabric")
+	cases := map[string]int{
+		"sha256":      32,
+		"sha384":      48,
+		"sha512":      64,
+		"sha3-256":    32,
+		"sha3-384":    48,
+		"sha3-512":    64,
+		"shake128":    32,
+		"shake256":    64,
+		"blake2b-256": 32,
+		"blake2b-512": 64,
+	}
+	for name, wantLen := range cases {
+		digest, err := ComputeCryptoHashWith(name, data)
+		if err != nil {
+			t.Errorf("ComputeCryptoHashWith(%q) failed: %s", name, err)
+			continue
+		}
+		if len(digest) != wantLen {
+			t.Errorf("ComputeCryptoHashWith(%q) returned %d bytes, want %d", name, len(digest), wantLen)
+		}
+	}
+}
+
+func TestComputeCryptoHashWithUnknownAlgorithm(t *testing.T) {
+	if _, err := ComputeCryptoHashWith("no-such-algorithm", []byte("data")); err == nil {
+		t.Fatal("ComputeCryptoHashWith with an unregistered name expected an error, got nil")
+	}
+}
+
+func TestSetDefaultHashAlgorithmUnknown(t *testing.T) {
+	if err := SetDefaultHashAlgorithm("no-such-algorithm"); err == nil {
+		t.Fatal("SetDefaultHashAlgorithm with an unregistered name expected an error, got nil")
+	}
+}
+
+func TestSetDefaultHashAlgorithmChangesComputeCryptoHash(t *testing.T) {
+	original := currentHashAlg()
+	defer func() {
+		if err := SetDefaultHashAlgorithm(original); err != nil {
+			t.Fatalf("failed to restore original default hash algorithm: %s", err)
+		}
+	}()
+
+	if err := SetDefaultHashAlgorithm("sha256"); err != nil {
+		t.Fatalf("SetDefaultHashAlgorithm(sha256) failed: %s", err)
+	}
+	if got := len(ComputeCryptoHash([]byte("data"))); got != 32 {
+		t.Errorf("ComputeCryptoHash() with default sha256 returned %d bytes, want 32", got)
+	}
+}
+
+func TestListHashAlgorithmsIncludesBuiltins(t *testing.T) {
+	names := ListHashAlgorithms()
+	want := []string{"sha256", "sha3-256", "shake256", "blake2b-256"}
+	for _, w := range want {
+		found := false
+		for _, n := range names {
+			if n == w {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("ListHashAlgorithms() = %v, missing built-in %q", names, w)
+		}
+	}
+}
+
+func TestRegisterHashAlgorithmOverride(t *testing.T) {
+	const name = "test-constant-hash"
+	RegisterHashAlgorithm(name, sha256.New)
+	RegisterHashAlgorithm(name, func() hash.Hash { return constHash{} })
+	digest, err := ComputeCryptoHashWith(name, []byte("anything"))
+	if err != nil {
+		t.Fatalf("ComputeCryptoHashWith(%q) failed: %s", name, err)
+	}
+	if string(digest) != "x" {
+		t.Fatalf("ComputeCryptoHashWith(%q) = %q, want %q", name, digest, "x")
+	}
+}
+
+func TestGenerateIDWithAlgUnknown(t *testing.T) {
+	if _, err := GenerateIDWithAlg("no-such-id-algorithm", "mychannel", []byte("creator"), []byte("nonce"), []byte("payload")); err == nil {
+		t.Fatal("GenerateIDWithAlg with an unregistered name expected an error, got nil")
+	}
+}
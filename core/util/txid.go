@@ -0,0 +1,92 @@
+/*
+Copyright IBM Corp. 2016 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+// NamespaceFabric is the root namespace UUID used to derive per-channel
+// namespaces for GenerateIDfromTxPayload. It was generated once with NewV5
+// against NamespaceURL and "fabric.hyperledger.org" and is fixed forever
+// after, per RFC 4122 section 4.3 ("the application should use a namespace
+// that is unique to it").
+var NamespaceFabric = MustParse("cf5284a2-c9d4-52ea-b511-a29a980f6610")
+
+const uuidv5Alg = "uuidv5"
+
+func init() {
+	RegisterIDAlgorithm(uuidv5Alg, GenerateIDfromTxPayload)
+}
+
+// channelNamespace derives the per-channel namespace UUID that
+// GenerateIDfromTxPayload hashes creator||nonce||payload into, so that two
+// channels can never collide even if a creator reuses a nonce across them.
+func channelNamespace(channelID string) UUID {
+	return NewV5(NamespaceFabric, []byte(channelID))
+}
+
+// GenerateIDfromTxPayload computes a deterministic transaction ID as a
+// UUIDv5 of creator||nonce||payload within a namespace scoped to channelID.
+// Unlike GenerateIDfromTxSHAHash, which hashes only the payload and so
+// collides whenever two submitters build byte-identical proposals,
+// including the creator and nonce here means only a single submitter's
+// single proposal can ever produce a given txID, while remaining
+// deterministic so that endorsing peers computing the ID independently
+// still agree. The result is a canonical UUID string, so downstream
+// systems such as block explorers and analytics pipelines can treat txIDs
+// as standard UUIDs rather than bare hex digests.
+func GenerateIDfromTxPayload(channelID string, creator []byte, nonce []byte, payload []byte) string {
+	namespace := channelNamespace(channelID)
+
+	name := make([]byte, 0, len(creator)+len(nonce)+len(payload))
+	name = append(name, creator...)
+	name = append(name, nonce...)
+	name = append(name, payload...)
+
+	return NewV5(namespace, name).String()
+}
+
+// IsLegacyTxID reports whether id is a legacy 64 character hex-encoded
+// SHA256 transaction ID, as opposed to a canonical UUID produced by
+// GenerateIDfromTxPayload or another "uuidv5"-registered algorithm.
+// Orderers use this during a rolling upgrade to accept both formats: nodes
+// still running the old binary emit legacy IDs until every node in the
+// channel has upgraded, at which point GenerateIDWithAlg can be switched to
+// "uuidv5" cluster-wide.
+func IsLegacyTxID(id string) bool {
+	if len(id) != 64 {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= '0' && c <= '9':
+		case c >= 'a' && c <= 'f':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateTxID reports whether id is well formed as either a legacy
+// hex-SHA256 transaction ID or a canonical UUID, so that validation code
+// can accept both formats during a rolling upgrade without caring which
+// algorithm a given peer generated it with.
+func ValidateTxID(id string) bool {
+	if IsLegacyTxID(id) {
+		return true
+	}
+	_, err := Parse(id)
+	return err == nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright IBM Corp. 2016 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+)
+
+func TestGenerateIDfromTxPayloadIsDeterministic(t *testing.T) {
+	creator, nonce, payload := []byte("creator"), []byte("nonce"), []byte("payload")
+
+	id1 := GenerateIDfromTxPayload("mychannel", creator, nonce, payload)
+	id2 := GenerateIDfromTxPayload("mychannel", creator, nonce, payload)
+	if id1 != id2 {
+		t.Fatalf("GenerateIDfromTxPayload is not deterministic: %s != %s", id1, id2)
+	}
+	if _, err := Parse(id1); err != nil {
+		t.Fatalf("GenerateIDfromTxPayload returned a non-canonical UUID %q: %s", id1, err)
+	}
+}
+
+func TestGenerateIDfromTxPayloadScopedByChannel(t *testing.T) {
+	creator, nonce, payload := []byte("creator"), []byte("nonce"), []byte("payload")
+
+	idA := GenerateIDfromTxPayload("channel-a", creator, nonce, payload)
+	idB := GenerateIDfromTxPayload("channel-b", creator, nonce, payload)
+	if idA == idB {
+		t.Fatal("GenerateIDfromTxPayload produced the same txID on two different channels")
+	}
+}
+
+func TestGenerateIDWithAlgUUIDv5(t *testing.T) {
+	channelID, creator, nonce, payload := "mychannel", []byte("creator"), []byte("nonce"), []byte("payload")
+	want := GenerateIDfromTxPayload(channelID, creator, nonce, payload)
+
+	got, err := GenerateIDWithAlg("uuidv5", channelID, creator, nonce, payload)
+	if err != nil {
+		t.Fatalf("GenerateIDWithAlg(uuidv5) failed: %s", err)
+	}
+	if got != want {
+		t.Fatalf("GenerateIDWithAlg(uuidv5) = %s, want %s", got, want)
+	}
+}
+
+func TestGenerateIDWithAlgUUIDv5AvoidsCreatorNonceCollision(t *testing.T) {
+	channelID, payload := "mychannel", []byte("payload")
+
+	id1, err := GenerateIDWithAlg("uuidv5", channelID, []byte("creator-1"), []byte("nonce-1"), payload)
+	if err != nil {
+		t.Fatalf("GenerateIDWithAlg(uuidv5) failed: %s", err)
+	}
+	id2, err := GenerateIDWithAlg("uuidv5", channelID, []byte("creator-2"), []byte("nonce-2"), payload)
+	if err != nil {
+		t.Fatalf("GenerateIDWithAlg(uuidv5) failed: %s", err)
+	}
+	if id1 == id2 {
+		t.Fatal("GenerateIDWithAlg(uuidv5) produced the same txID for two different creator/nonce pairs on an identical payload")
+	}
+}
+
+func TestIsLegacyTxID(t *testing.T) {
+	legacy := GenerateIDfromTxSHAHash([]byte("payload"))
+	if !IsLegacyTxID(legacy) {
+		t.Errorf("IsLegacyTxID(%q) = false, want true", legacy)
+	}
+
+	uuidv5 := GenerateIDfromTxPayload("mychannel", []byte("c"), []byte("n"), []byte("p"))
+	if IsLegacyTxID(uuidv5) {
+		t.Errorf("IsLegacyTxID(%q) = true, want false", uuidv5)
+	}
+}
+
+func TestValidateTxIDAcceptsBothFormats(t *testing.T) {
+	legacy := GenerateIDfromTxSHAHash([]byte("payload"))
+	uuidv5 := GenerateIDfromTxPayload("mychannel", []byte("c"), []byte("n"), []byte("p"))
+
+	if !ValidateTxID(legacy) {
+		t.Errorf("ValidateTxID(%q) = false, want true", legacy)
+	}
+	if !ValidateTxID(uuidv5) {
+		t.Errorf("ValidateTxID(%q) = false, want true", uuidv5)
+	}
+	if ValidateTxID("not-a-valid-id") {
+		t.Error("ValidateTxID(\"not-a-valid-id\") = true, want false")
+	}
+}
@@ -23,28 +23,52 @@ import (
 	"io"
 	"math/big"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/hyperledger/fabric/common/metadata"
-	"golang.org/x/crypto/sha3"
 )
 
-type alg struct {
-	hashFun func([]byte) string
-}
-
 const defaultAlg = "sha256"
 
-var availableIDgenAlgs = map[string]alg{
-	defaultAlg: {GenerateIDfromTxSHAHash},
-}
-
-// ComputeCryptoHash should be used in openchain code so that we can change the actual algo used for crypto-hash at one place
+// idGenRegistry holds the ID generation functions available to
+// GenerateIDWithAlg, seeded with the legacy SHA256 algorithm below. Plugins
+// add to it with RegisterIDAlgorithm. channelID, creator and nonce are
+// passed through so that algorithms such as "uuidv5" (see txid.go) can mix
+// them in for collision resistance; payload-only algorithms such as the
+// legacy "sha256" are free to ignore them.
+var idGenRegistry = struct {
+	sync.RWMutex
+	algs map[string]func(channelID string, creator, nonce, payload []byte) string
+}{
+	algs: map[string]func(channelID string, creator, nonce, payload []byte) string{
+		defaultAlg: func(channelID string, creator, nonce, payload []byte) string {
+			return GenerateIDfromTxSHAHash(payload)
+		},
+	},
+}
+
+// RegisterIDAlgorithm makes an ID generation function available under name
+// to GenerateIDWithAlg. It is safe to call concurrently and may be used to
+// override the built-in "sha256" algorithm.
+func RegisterIDAlgorithm(name string, fn func(channelID string, creator, nonce, payload []byte) string) {
+	idGenRegistry.Lock()
+	defer idGenRegistry.Unlock()
+	idGenRegistry.algs[name] = fn
+}
+
+// ComputeCryptoHash should be used in openchain code so that we can change the actual algo used for crypto-hash at one place.
+// The algorithm used is the one most recently selected with SetDefaultHashAlgorithm (SHAKE256, producing a 64 byte digest,
+// unless a deployment has set e.g. BCCSP.Hash to something else at process start).
 func ComputeCryptoHash(data []byte) (hash []byte) {
-	hash = make([]byte, 64)
-	sha3.ShakeSum256(hash, data)
-	return
+	hash, err := ComputeCryptoHashWith(currentHashAlg(), data)
+	if err != nil {
+		// the default algorithm is only ever changed through SetDefaultHashAlgorithm,
+		// which validates the name against the registry, so this cannot happen.
+		panic(err)
+	}
+	return hash
 }
 
 // GenerateBytesUUID returns a UUID based on RFC 4122 returning the generated bytes
@@ -95,16 +119,21 @@ func GenerateIDfromTxSHAHash(payload []byte) string {
 	return fmt.Sprintf("%x", sha256.Sum256(payload))
 }
 
-// GenerateIDWithAlg generates an ID using a custom algorithm
-func GenerateIDWithAlg(customIDgenAlg string, payload []byte) (string, error) {
+// GenerateIDWithAlg generates an ID using a custom algorithm. channelID,
+// creator and nonce are made available to the algorithm so that
+// collision-resistant algorithms such as "uuidv5" can mix them into the ID;
+// payload-only algorithms such as the legacy "sha256" ignore them.
+func GenerateIDWithAlg(customIDgenAlg string, channelID string, creator []byte, nonce []byte, payload []byte) (string, error) {
 	if customIDgenAlg == "" {
 		customIDgenAlg = defaultAlg
 	}
-	var alg = availableIDgenAlgs[customIDgenAlg]
-	if alg.hashFun != nil {
-		return alg.hashFun(payload), nil
+	idGenRegistry.RLock()
+	fn, ok := idGenRegistry.algs[customIDgenAlg]
+	idGenRegistry.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("Wrong ID generation algorithm was given: %s", customIDgenAlg)
 	}
-	return "", fmt.Errorf("Wrong ID generation algorithm was given: %s", customIDgenAlg)
+	return fn(channelID, creator, nonce, payload), nil
 }
 
 func idBytesToStr(id []byte) string {
@@ -0,0 +1,347 @@
+/*
+Copyright IBM Corp. 2016 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bufio"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha1"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// UUID is a 128-bit RFC 4122 universally unique identifier. Unlike the
+// legacy GenerateUUID/GenerateBytesUUID helpers in utils.go, which are fixed to
+// version 4, UUID supports parsing and generating the version 1, 3, 5 and 7
+// variants required to interoperate with external systems and to produce
+// deterministic or time-ordered identifiers.
+type UUID [16]byte
+
+// Nil is the zero-value UUID, i.e. all 128 bits set to zero.
+var Nil UUID
+
+// Predefined namespaces for NewV3 and NewV5, as defined in RFC 4122 Appendix C.
+var (
+	NamespaceDNS  = MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	NamespaceURL  = MustParse("6ba7b811-9dad-11d1-80b4-00c04fd430c8")
+	NamespaceOID  = MustParse("6ba7b812-9dad-11d1-80b4-00c04fd430c8")
+	NamespaceX500 = MustParse("6ba7b814-9dad-11d1-80b4-00c04fd430c8")
+)
+
+// randPool is a process-wide buffered reader over crypto/rand, guarded by a
+// mutex, so that generating large numbers of UUIDs under heavy orderer load
+// does not incur a syscall per UUID.
+var randPool = struct {
+	sync.Mutex
+	r *bufio.Reader
+}{r: bufio.NewReaderSize(rand.Reader, 4096)}
+
+func randomBytes(b []byte) error {
+	randPool.Lock()
+	defer randPool.Unlock()
+	_, err := io.ReadFull(randPool.r, b)
+	return err
+}
+
+// Parse decodes s as a UUID. The canonical
+// "xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx" form, the "urn:uuid:" prefixed
+// form, and a bare 32 character hex string are all accepted.
+func Parse(s string) (UUID, error) {
+	var u UUID
+
+	s = strings.TrimPrefix(s, "urn:uuid:")
+	s = strings.TrimPrefix(s, "{")
+	s = strings.TrimSuffix(s, "}")
+
+	switch len(s) {
+	case 32:
+		// bare hex, no dashes
+	case 36:
+		if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return u, fmt.Errorf("uuid: invalid format for %q", s)
+		}
+		s = s[:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+	default:
+		return u, fmt.Errorf("uuid: invalid length %d for %q", len(s), s)
+	}
+
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return u, fmt.Errorf("uuid: invalid hex in %q: %s", s, err)
+	}
+	copy(u[:], b)
+	return u, nil
+}
+
+// MustParse is like Parse but panics if s cannot be parsed. It is intended
+// for use with package-level UUID literals such as the Namespace constants.
+func MustParse(s string) UUID {
+	u, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+// FromBytes creates a UUID from a 16 byte slice.
+func FromBytes(b []byte) (UUID, error) {
+	var u UUID
+	if len(b) != 16 {
+		return u, fmt.Errorf("uuid: invalid length %d, expected 16 bytes", len(b))
+	}
+	copy(u[:], b)
+	return u, nil
+}
+
+// Bytes returns the raw 16 bytes of u.
+func (u UUID) Bytes() []byte {
+	b := make([]byte, 16)
+	copy(b, u[:])
+	return b
+}
+
+// Version returns the RFC 4122 version of u (1, 3, 4, 5 or 7 for the
+// variants this package generates).
+func (u UUID) Version() int {
+	return int(u[6] >> 4)
+}
+
+// Variant returns the RFC 4122 variant byte pattern encoded in u[8]. Values
+// generated by this package are always the RFC 4122 variant, 0b10.
+func (u UUID) Variant() byte {
+	return u[8] >> 6
+}
+
+// String returns the canonical "xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx"
+// representation of u.
+func (u UUID) String() string {
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:])
+}
+
+// URN returns u in the "urn:uuid:" form used by XML and other IETF specs.
+func (u UUID) URN() string {
+	return "urn:uuid:" + u.String()
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, returning the raw 16
+// bytes so that a UUID can be carried in a protobuf `bytes` field.
+func (u UUID) MarshalBinary() ([]byte, error) {
+	return u.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (u *UUID) UnmarshalBinary(data []byte) error {
+	parsed, err := FromBytes(data)
+	if err != nil {
+		return err
+	}
+	*u = parsed
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler, used by encoding/json.
+func (u UUID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, used by encoding/json.
+func (u *UUID) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*u = parsed
+	return nil
+}
+
+// Value implements database/sql/driver.Valuer so a UUID can be written
+// through a standard SQL driver.
+func (u UUID) Value() (driver.Value, error) {
+	return u.String(), nil
+}
+
+// Scan implements database/sql.Scanner, accepting the string, []byte or
+// canonical UUID forms a driver may hand back.
+func (u *UUID) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*u = Nil
+		return nil
+	case string:
+		parsed, err := Parse(v)
+		if err != nil {
+			return err
+		}
+		*u = parsed
+		return nil
+	case []byte:
+		if len(v) == 16 {
+			parsed, err := FromBytes(v)
+			if err != nil {
+				return err
+			}
+			*u = parsed
+			return nil
+		}
+		parsed, err := Parse(string(v))
+		if err != nil {
+			return err
+		}
+		*u = parsed
+		return nil
+	case UUID:
+		*u = v
+		return nil
+	default:
+		return fmt.Errorf("uuid: unsupported Scan type %T", src)
+	}
+}
+
+func setVersionAndVariant(u *UUID, version byte) {
+	u[6] = u[6]&^0xf0 | (version << 4)
+	u[8] = u[8]&^0xc0 | 0x80
+}
+
+// NewV1 returns a new time and MAC address based UUID, as described in RFC
+// 4122 section 4.2. If no hardware interface with a MAC address can be
+// found, a random node identifier is used instead with the multicast bit
+// set, per RFC 4122 section 4.5, so that it cannot collide with a real MAC.
+func NewV1() (UUID, error) {
+	var u UUID
+
+	node, err := hardwareAddrFunc()
+	if err != nil {
+		node = make([]byte, 6)
+		if err := randomBytes(node); err != nil {
+			return u, err
+		}
+		node[0] |= 0x01
+	}
+
+	now := time.Now()
+	// 100-nanosecond intervals since the RFC 4122 epoch (1582-10-15).
+	ts := uint64(now.UnixNano()/100) + 0x01b21dd213814000
+
+	timeLow := uint32(ts & 0xffffffff)
+	timeMid := uint16((ts >> 32) & 0xffff)
+	timeHi := uint16((ts >> 48) & 0x0fff)
+
+	var clockSeq [2]byte
+	if err := randomBytes(clockSeq[:]); err != nil {
+		return u, err
+	}
+	clockSeq[0] &^= 0xc0
+
+	u[0] = byte(timeLow >> 24)
+	u[1] = byte(timeLow >> 16)
+	u[2] = byte(timeLow >> 8)
+	u[3] = byte(timeLow)
+	u[4] = byte(timeMid >> 8)
+	u[5] = byte(timeMid)
+	u[6] = byte(timeHi >> 8)
+	u[7] = byte(timeHi)
+	u[8] = clockSeq[0]
+	u[9] = clockSeq[1]
+	copy(u[10:], node)
+
+	setVersionAndVariant(&u, 1)
+	return u, nil
+}
+
+// hardwareAddrFunc is a var so tests can force NewV1's random-node fallback
+// path without depending on what network interfaces the host happens to have.
+var hardwareAddrFunc = hardwareAddr
+
+// hardwareAddr returns the first non-zero hardware MAC address found on the
+// host, or an error if none is available.
+func hardwareAddr() ([]byte, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+	for _, iface := range ifaces {
+		if len(iface.HardwareAddr) < 6 {
+			continue
+		}
+		addr := iface.HardwareAddr[:6]
+		for _, b := range addr {
+			if b != 0 {
+				return addr, nil
+			}
+		}
+	}
+	return nil, errors.New("uuid: no hardware address found")
+}
+
+// NewV3 returns a new namespace-based UUID using MD5, as described in RFC
+// 4122 section 4.3.
+func NewV3(namespace UUID, name []byte) UUID {
+	h := md5.New()
+	h.Write(namespace[:])
+	h.Write(name)
+	return newFromHash(h.Sum(nil), 3)
+}
+
+// NewV5 returns a new namespace-based UUID using SHA-1, as described in RFC
+// 4122 section 4.3.
+func NewV5(namespace UUID, name []byte) UUID {
+	h := sha1.New()
+	h.Write(namespace[:])
+	h.Write(name)
+	return newFromHash(h.Sum(nil), 5)
+}
+
+func newFromHash(sum []byte, version byte) UUID {
+	var u UUID
+	copy(u[:], sum[:16])
+	setVersionAndVariant(&u, version)
+	return u
+}
+
+// NewV7 returns a new draft-RFC-9562 time-ordered UUID: the first 48 bits
+// are the Unix epoch in milliseconds (big-endian), followed by the version
+// and variant bits interleaved with random data. Because the timestamp
+// sorts lexicographically ahead of the random tail, V7 UUIDs make good
+// transaction identifiers for the ledger: they sort by creation time to
+// millisecond precision, without leaking a MAC address the way NewV1 does.
+// UUIDs created within the same millisecond sort in random order.
+func NewV7() (UUID, error) {
+	var u UUID
+
+	ms := uint64(time.Now().UnixMilli())
+	u[0] = byte(ms >> 40)
+	u[1] = byte(ms >> 32)
+	u[2] = byte(ms >> 24)
+	u[3] = byte(ms >> 16)
+	u[4] = byte(ms >> 8)
+	u[5] = byte(ms)
+
+	if err := randomBytes(u[6:]); err != nil {
+		return u, err
+	}
+
+	setVersionAndVariant(&u, 7)
+	return u, nil
+}
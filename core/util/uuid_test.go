@@ -0,0 +1,222 @@
+/*
+Copyright IBM Corp. 2016 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseRoundTrip(t *testing.T) {
+	u, err := NewV7()
+	if err != nil {
+		t.Fatalf("NewV7 failed: %s", err)
+	}
+
+	parsed, err := Parse(u.String())
+	if err != nil {
+		t.Fatalf("Parse(%q) failed: %s", u.String(), err)
+	}
+	if parsed != u {
+		t.Fatalf("round trip mismatch: got %s, want %s", parsed, u)
+	}
+
+	// urn:uuid: prefix and bare hex forms must parse to the same value.
+	if parsed, err = Parse(u.URN()); err != nil || parsed != u {
+		t.Fatalf("Parse(URN) = %s, %v; want %s, nil", parsed, err, u)
+	}
+	bare := u.String()
+	bare = bare[0:8] + bare[9:13] + bare[14:18] + bare[19:23] + bare[24:]
+	if parsed, err = Parse(bare); err != nil || parsed != u {
+		t.Fatalf("Parse(bare hex) = %s, %v; want %s, nil", parsed, err, u)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	badInputs := []string{
+		"",
+		"not-a-uuid",
+		"6ba7b810-9dad-11d1-80b4-00c04fd430c",     // too short
+		"6ba7b810-9dad-11d1-80b4-00c04fd430c8ff",  // too long
+		"6ba7b8109dad11d180b400c04fd430c8z",        // invalid hex char, wrong length
+		"6ba7b810x9dad-11d1-80b4-00c04fd430c8",     // misplaced dash
+	}
+	for _, in := range badInputs {
+		if _, err := Parse(in); err == nil {
+			t.Errorf("Parse(%q) expected an error, got nil", in)
+		}
+	}
+}
+
+func TestNewV5KnownVector(t *testing.T) {
+	// Cross-checked against Python's uuid.uuid5(uuid.NAMESPACE_DNS, "www.example.com").
+	got := NewV5(NamespaceDNS, []byte("www.example.com"))
+	want := MustParse("2ed6657d-e927-568b-95e1-2665a8aea6a2")
+	if got != want {
+		t.Fatalf("NewV5(NamespaceDNS, ...) = %s, want %s", got, want)
+	}
+	if got.Version() != 5 {
+		t.Errorf("Version() = %d, want 5", got.Version())
+	}
+}
+
+func TestNewV3KnownVector(t *testing.T) {
+	// Cross-checked against Python's uuid.uuid3(uuid.NAMESPACE_DNS, "www.example.com").
+	got := NewV3(NamespaceDNS, []byte("www.example.com"))
+	want := MustParse("5df41881-3aed-3515-88a7-2f4a814cf09e")
+	if got != want {
+		t.Fatalf("NewV3(NamespaceDNS, ...) = %s, want %s", got, want)
+	}
+	if got.Version() != 3 {
+		t.Errorf("Version() = %d, want 3", got.Version())
+	}
+}
+
+func TestNewV3AndV5AreDeterministic(t *testing.T) {
+	name := []byte("fabric-chaincode-ns")
+	if NewV3(NamespaceURL, name) != NewV3(NamespaceURL, name) {
+		t.Error("NewV3 is not deterministic for the same namespace and name")
+	}
+	if NewV5(NamespaceURL, name) != NewV5(NamespaceURL, name) {
+		t.Error("NewV5 is not deterministic for the same namespace and name")
+	}
+	if NewV5(NamespaceURL, name) == NewV5(NamespaceDNS, name) {
+		t.Error("NewV5 produced the same UUID for two different namespaces")
+	}
+}
+
+func TestNewV1Layout(t *testing.T) {
+	u, err := NewV1()
+	if err != nil {
+		t.Fatalf("NewV1 failed: %s", err)
+	}
+
+	if got := u.Version(); got != 1 {
+		t.Errorf("Version() = %d, want 1", got)
+	}
+	if got := u.Variant(); got != 0b10 {
+		t.Errorf("Variant() = %b, want 0b10", got)
+	}
+
+	parsed, err := Parse(u.String())
+	if err != nil {
+		t.Fatalf("Parse(%q) failed: %s", u.String(), err)
+	}
+	if parsed != u {
+		t.Fatalf("round trip mismatch: got %s, want %s", parsed, u)
+	}
+}
+
+func TestNewV1RandomNodeFallbackSetsMulticastBit(t *testing.T) {
+	original := hardwareAddrFunc
+	hardwareAddrFunc = func() ([]byte, error) {
+		return nil, errors.New("no hardware address found (forced for test)")
+	}
+	defer func() { hardwareAddrFunc = original }()
+
+	u, err := NewV1()
+	if err != nil {
+		t.Fatalf("NewV1 failed: %s", err)
+	}
+
+	node := u[10:]
+	if node[0]&0x01 == 0 {
+		t.Fatalf("random node fallback must set the multicast bit, got % x", node)
+	}
+}
+
+func TestNewV7Layout(t *testing.T) {
+	before := uint64(time.Now().UnixMilli())
+	u, err := NewV7()
+	if err != nil {
+		t.Fatalf("NewV7 failed: %s", err)
+	}
+	after := uint64(time.Now().UnixMilli())
+
+	if got := u.Version(); got != 7 {
+		t.Errorf("Version() = %d, want 7", got)
+	}
+	if got := u.Variant(); got != 0b10 {
+		t.Errorf("Variant() = %b, want 0b10", got)
+	}
+
+	ms := uint64(u[0])<<40 | uint64(u[1])<<32 | uint64(u[2])<<24 | uint64(u[3])<<16 | uint64(u[4])<<8 | uint64(u[5])
+	if ms < before || ms > after {
+		t.Errorf("embedded timestamp %d not within [%d, %d]", ms, before, after)
+	}
+}
+
+func TestUUIDTextAndBinaryMarshal(t *testing.T) {
+	u, err := NewV7()
+	if err != nil {
+		t.Fatalf("NewV7 failed: %s", err)
+	}
+
+	text, err := u.MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText failed: %s", err)
+	}
+	var fromText UUID
+	if err := fromText.UnmarshalText(text); err != nil {
+		t.Fatalf("UnmarshalText failed: %s", err)
+	}
+	if fromText != u {
+		t.Fatalf("UnmarshalText(MarshalText()) = %s, want %s", fromText, u)
+	}
+
+	bin, err := u.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %s", err)
+	}
+	var fromBin UUID
+	if err := fromBin.UnmarshalBinary(bin); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %s", err)
+	}
+	if fromBin != u {
+		t.Fatalf("UnmarshalBinary(MarshalBinary()) = %s, want %s", fromBin, u)
+	}
+}
+
+func TestUUIDScan(t *testing.T) {
+	u, err := NewV7()
+	if err != nil {
+		t.Fatalf("NewV7 failed: %s", err)
+	}
+
+	cases := []interface{}{u.String(), u.Bytes(), u}
+	for _, src := range cases {
+		var scanned UUID
+		if err := scanned.Scan(src); err != nil {
+			t.Errorf("Scan(%v) failed: %s", src, err)
+			continue
+		}
+		if scanned != u {
+			t.Errorf("Scan(%v) = %s, want %s", src, scanned, u)
+		}
+	}
+
+	var nilScan UUID
+	if err := nilScan.Scan(nil); err != nil || nilScan != Nil {
+		t.Errorf("Scan(nil) = %s, %v; want Nil, nil", nilScan, err)
+	}
+
+	var bad UUID
+	if err := bad.Scan(42); err == nil {
+		t.Error("Scan(42) expected an error, got nil")
+	}
+}